@@ -0,0 +1,26 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+)
+
+// Logger wraps slog with a fixed per-instance alias attached to every
+// line, so log output from several client instances can be told apart.
+type Logger struct {
+	alias string
+	base  *slog.Logger
+}
+
+// NewLogger returns a JSON structured logger that tags every line with
+// alias.
+func NewLogger(alias string) *Logger {
+	handler := slog.NewJSONHandler(os.Stdout, nil)
+	return &Logger{alias: alias, base: slog.New(handler).With("alias", alias)}
+}
+
+// ForRequest returns a logger annotated with a per-call request ID, for
+// use around a single RPC.
+func (l *Logger) ForRequest(requestID string) *slog.Logger {
+	return l.base.With("request_id", requestID)
+}
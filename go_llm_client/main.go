@@ -4,25 +4,37 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
 	"time"
 
 	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/google/uuid"
 
 	"go_llm_client/llm"
 )
 
+// upstreamAddr is the Thrift server this client talks to, reused as the
+// "upstream" field on every log line.
+const upstreamAddr = "localhost:9090"
+
 func main() {
+	alias := os.Getenv("LLM_CLIENT_ALIAS")
+	if alias == "" {
+		alias = "llm-client"
+	}
+	logger := NewLogger(alias)
+
 	conf := &thrift.TConfiguration{
 		ConnectTimeout: 30 * time.Second,
 		SocketTimeout:  30 * time.Second,
 	}
-	
-	transport := thrift.NewTSocketConf("localhost:9090", conf)
+
+	transport := thrift.NewTSocketConf(upstreamAddr, conf)
 	defer transport.Close()
 
 	protocolFactory := thrift.NewTBinaryProtocolFactoryConf(conf)
 	protocol := protocolFactory.GetProtocol(transport)
-	
+
 	client := llm.NewLanguageModelServiceClient(thrift.NewTStandardClient(protocol, protocol))
 
 	if err := transport.Open(); err != nil {
@@ -38,7 +50,10 @@ func main() {
 	}
 
 	fmt.Printf("\nGenerating text with prompt: %s\n", generateRequest.Prompt)
+	requestID := uuid.NewString()
+	start := time.Now()
 	genResult, err := client.GenerateText(ctx, generateRequest)
+	logRPC(logger, requestID, "GenerateText", start, err)
 	if err != nil {
 		log.Fatal("Error calling GenerateText:", err)
 	}
@@ -56,7 +71,10 @@ func main() {
 	}
 
 	fmt.Printf("Classifying text: %s\n", classifyRequest.Text)
+	requestID = uuid.NewString()
+	start = time.Now()
 	classResult, err := client.ClassifyText(ctx, classifyRequest)
+	logRPC(logger, requestID, "ClassifyText", start, err)
 	if err != nil {
 		log.Fatal("Error calling ClassifyText:", err)
 	}
@@ -76,7 +94,10 @@ func main() {
 	}
 
 	fmt.Printf("\nClassifying text: %s\n", techClassifyRequest.Text)
+	requestID = uuid.NewString()
+	start = time.Now()
 	techClassResult, err := client.ClassifyText(ctx, techClassifyRequest)
+	logRPC(logger, requestID, "ClassifyText", start, err)
 	if err != nil {
 		log.Fatal("Error calling ClassifyText:", err)
 	}
@@ -84,4 +105,15 @@ func main() {
 		techClassResult.ClassificationTime,
 		techClassResult.Label,
 		techClassResult.Confidence)
+}
+
+// logRPC records one outbound RPC's latency and outcome through logger.
+func logRPC(logger *Logger, requestID, method string, start time.Time, err error) {
+	line := logger.ForRequest(requestID)
+	latencyMs := time.Since(start).Milliseconds()
+	if err != nil {
+		line.Error("rpc call failed", "method", method, "upstream", upstreamAddr, "latency_ms", latencyMs, "status", "error", "error", err.Error())
+		return
+	}
+	line.Info("rpc call succeeded", "method", method, "upstream", upstreamAddr, "latency_ms", latencyMs, "status", "ok")
 }
\ No newline at end of file
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/nats-io/nats.go"
+
+	"github.com/example/weather-stream-server/weather"
+)
+
+// reconnectDelay is how long to wait before retrying a dropped subscription.
+const reconnectDelay = 5 * time.Second
+
+func main() {
+	conf := &thrift.TConfiguration{
+		ConnectTimeout: 30 * time.Second,
+		SocketTimeout:  30 * time.Second,
+	}
+
+	transport := thrift.NewTSocketConf("localhost:9091", conf)
+	defer transport.Close()
+
+	protocolFactory := thrift.NewTBinaryProtocolFactoryConf(conf)
+	protocol := protocolFactory.GetProtocol(transport)
+
+	client := weather.NewWeatherMonitorServiceClient(thrift.NewTStandardClient(protocol, protocol))
+
+	if err := transport.Open(); err != nil {
+		log.Fatal("Error opening transport:", err)
+	}
+
+	natsConn, err := nats.Connect(nats.DefaultURL)
+	if err != nil {
+		log.Fatal("Error connecting to NATS:", err)
+	}
+	defer natsConn.Close()
+
+	ctx := context.Background()
+
+	ack, err := client.SubscribeTemperature(ctx, &weather.StreamRequest{
+		Locations:       []string{"london", "tokyo", "paris"},
+		IntervalSeconds: 10,
+	})
+	if err != nil {
+		log.Fatal("Error calling SubscribeTemperature:", err)
+	}
+
+	fmt.Printf("Subscribed (id=%s), listening on %s\n", ack.SubscriptionId, ack.Subject)
+	listen(natsConn, protocolFactory, ack.Subject)
+}
+
+// listen reads TemperatureReading frames off subject until interrupted,
+// waiting reconnectDelay before resubscribing whenever a subscription fails
+// or no frame arrives within 30 seconds.
+func listen(conn *nats.Conn, protocolFactory thrift.TProtocolFactory, subject string) {
+	for {
+		sub, err := conn.SubscribeSync(subject)
+		if err != nil {
+			log.Printf("subscribe failed, retrying in %s: %v\n", reconnectDelay, err)
+			time.Sleep(reconnectDelay)
+			continue
+		}
+
+		for {
+			msg, err := sub.NextMsg(30 * time.Second)
+			if err != nil {
+				log.Printf("lost subscription, reconnecting in %s: %v\n", reconnectDelay, err)
+				time.Sleep(reconnectDelay)
+				break
+			}
+
+			reading := &weather.TemperatureReading{}
+			transport := thrift.NewTMemoryBuffer()
+			if _, err := transport.Write(msg.Data); err != nil {
+				log.Printf("failed to buffer frame: %v\n", err)
+				continue
+			}
+			if err := reading.Read(context.Background(), protocolFactory.GetProtocol(transport)); err != nil {
+				log.Printf("failed to decode reading: %v\n", err)
+				continue
+			}
+
+			fmt.Printf("[%s] %.2f°C at %d\n", reading.Location, reading.Temperature, reading.Timestamp)
+		}
+	}
+}
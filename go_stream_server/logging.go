@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+)
+
+// correlationIDKey is the context.Context key an InstrumentedProcessor
+// stamps every inbound request with.
+type correlationIDKey struct{}
+
+// WithCorrelationID attaches id to ctx for downstream logging.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationIDFromContext returns the correlation ID attached to ctx, if
+// any.
+func CorrelationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+// Logger wraps slog with a fixed per-instance alias attached to every
+// line, so log output from several server instances can be told apart.
+type Logger struct {
+	alias string
+	base  *slog.Logger
+}
+
+// NewLogger returns a JSON structured logger that tags every line with
+// alias.
+func NewLogger(alias string) *Logger {
+	handler := slog.NewJSONHandler(os.Stdout, nil)
+	return &Logger{alias: alias, base: slog.New(handler).With("alias", alias)}
+}
+
+// WithContext returns a logger annotated with ctx's correlation ID, if
+// any, for use at a single call site.
+func (l *Logger) WithContext(ctx context.Context) *slog.Logger {
+	if id := CorrelationIDFromContext(ctx); id != "" {
+		return l.base.With("correlation_id", id)
+	}
+	return l.base
+}
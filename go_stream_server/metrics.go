@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// latencyBucketsMs are the upper bounds (inclusive) of the request latency
+// histogram exposed at /metrics as weather_upstream_latency_ms.
+var latencyBucketsMs = []float64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000}
+
+// Metrics tracks request/error counts and a histogram of whole-request
+// processing latency, rendered in Prometheus text exposition format by
+// ServeHTTP. Upstream weather API calls are not timed separately; their
+// time is only part of each request's latency.
+type Metrics struct {
+	requestCount int64
+	errorCount   int64
+
+	mu              sync.Mutex
+	latencyCounts   []int64
+	latencySum      float64
+	latencyObserved int64
+}
+
+// NewMetrics returns an empty Metrics ready to be registered on a
+// /metrics handler.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		latencyCounts: make([]int64, len(latencyBucketsMs)),
+	}
+}
+
+// RecordRequest records one processed request's latency and whether it
+// failed.
+func (m *Metrics) RecordRequest(latency time.Duration, failed bool) {
+	atomic.AddInt64(&m.requestCount, 1)
+	if failed {
+		atomic.AddInt64(&m.errorCount, 1)
+	}
+
+	ms := float64(latency.Milliseconds())
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.latencySum += ms
+	m.latencyObserved++
+	for i, bound := range latencyBucketsMs {
+		if ms <= bound {
+			m.latencyCounts[i]++
+		}
+	}
+}
+
+// ServeHTTP renders the current counters in Prometheus text exposition
+// format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fmt.Fprintln(w, "# HELP weather_requests_total Total Thrift requests processed.")
+	fmt.Fprintln(w, "# TYPE weather_requests_total counter")
+	fmt.Fprintf(w, "weather_requests_total %d\n", atomic.LoadInt64(&m.requestCount))
+
+	fmt.Fprintln(w, "# HELP weather_request_errors_total Total Thrift requests that returned an error.")
+	fmt.Fprintln(w, "# TYPE weather_request_errors_total counter")
+	fmt.Fprintf(w, "weather_request_errors_total %d\n", atomic.LoadInt64(&m.errorCount))
+
+	fmt.Fprintln(w, "# HELP weather_upstream_latency_ms Request processing latency in milliseconds.")
+	fmt.Fprintln(w, "# TYPE weather_upstream_latency_ms histogram")
+	for i, bound := range latencyBucketsMs {
+		fmt.Fprintf(w, "weather_upstream_latency_ms_bucket{le=\"%g\"} %d\n", bound, m.latencyCounts[i])
+	}
+	fmt.Fprintf(w, "weather_upstream_latency_ms_bucket{le=\"+Inf\"} %d\n", m.latencyObserved)
+	fmt.Fprintf(w, "weather_upstream_latency_ms_sum %g\n", m.latencySum)
+	fmt.Fprintf(w, "weather_upstream_latency_ms_count %d\n", m.latencyObserved)
+}
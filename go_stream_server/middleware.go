@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/google/uuid"
+)
+
+// InstrumentedProcessor wraps a thrift.TProcessor to stamp every inbound
+// request with a correlation ID, log it through logger, and record it in
+// metrics before returning the wrapped processor's result unchanged. It
+// embeds the wrapped TProcessor so ProcessorMap/AddToProcessorMap are
+// promoted unchanged, leaving only Process overridden.
+type InstrumentedProcessor struct {
+	thrift.TProcessor
+	metrics *Metrics
+	logger  *Logger
+}
+
+// NewInstrumentedProcessor wraps inner with correlation ID generation,
+// structured logging, and Prometheus metrics recording.
+func NewInstrumentedProcessor(inner thrift.TProcessor, metrics *Metrics, logger *Logger) *InstrumentedProcessor {
+	return &InstrumentedProcessor{TProcessor: inner, metrics: metrics, logger: logger}
+}
+
+func (p *InstrumentedProcessor) Process(ctx context.Context, in, out thrift.TProtocol) (bool, thrift.TException) {
+	ctx = WithCorrelationID(ctx, uuid.NewString())
+	start := time.Now()
+
+	ok, err := p.TProcessor.Process(ctx, in, out)
+
+	latency := time.Since(start)
+	p.metrics.RecordRequest(latency, err != nil)
+
+	logLine := p.logger.WithContext(ctx)
+	if err != nil {
+		logLine.Error("processed request", "latency_ms", latency.Milliseconds(), "success", ok, "error", err.Error())
+	} else {
+		logLine.Info("processed request", "latency_ms", latency.Milliseconds(), "success", ok)
+	}
+
+	return ok, err
+}
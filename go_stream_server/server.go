@@ -2,24 +2,105 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"strconv"
 	"time"
 
 	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/nats-io/nats.go"
+
 	utils "github.com/example/weather-stream-server/utils"
 	"github.com/example/weather-stream-server/weather"
 )
 
-type WeatherMonitorHandler struct{}
+// maxStreamSubscriptions caps how many concurrent SubscribeTemperature
+// publisher goroutines a single server will run.
+const maxStreamSubscriptions = 500
+
+// defaultCacheMaxAge is how long a cached weather response is served
+// before a fresh fetch is attempted, unless overridden by
+// WEATHER_CACHE_MAX_AGE.
+const defaultCacheMaxAge = 10 * time.Minute
+
+// defaultCacheLocation is where cached weather responses are persisted
+// unless overridden by WEATHER_CACHE_LOCATION.
+const defaultCacheLocation = "./weather-cache"
+
+// defaultGeocodeCacheLocation and defaultGeocodeLRUSize configure the
+// on-disk and in-memory layers of the geocoding cache built in main.
+const defaultGeocodeCacheLocation = "./geocode-cache"
+const defaultGeocodeLRUSize = 100
+
+type WeatherMonitorHandler struct {
+	subs     *SubscriptionManager
+	cache    *utils.Cache
+	geocoder utils.Geocoder
+	logger   *Logger
+}
+
+// NewWeatherMonitorHandler wires a handler up to a SubscriptionManager that
+// publishes streamed readings over natsConn, capped at maxSubscriptions
+// concurrent publisher goroutines, an on-disk cache (configured by
+// cacheConfig) in front of provider that shields upstream rate limits and
+// outages from callers, and geocoder to resolve location names to
+// coordinates. alias tags every line the handler logs through its
+// structured logger, so several instances can be told apart; the
+// subscription publishers and buildForecast log through the standard log
+// package and carry no alias.
+func NewWeatherMonitorHandler(natsConn *nats.Conn, protocolFactory thrift.TProtocolFactory, provider utils.WeatherProvider, geocoder utils.Geocoder, alias string, maxSubscriptions int, cacheConfig utils.CacheConfig) *WeatherMonitorHandler {
+	cache, err := utils.NewCache(cacheConfig, provider)
+	if err != nil {
+		log.Fatalf("Failed to initialize weather cache: %v", err)
+	}
+
+	h := &WeatherMonitorHandler{cache: cache, geocoder: geocoder, logger: NewLogger(alias)}
+	h.subs = NewSubscriptionManager(natsConn, protocolFactory, maxSubscriptions, h.lookupTemperature)
+	return h
+}
+
+func (h *WeatherMonitorHandler) lookupTemperature(location string) (*weather.TemperatureReading, error) {
+	resolved, err := utils.ResolveLocation(h.geocoder, location)
+	if err != nil {
+		return nil, err
+	}
+
+	weatherData, err := h.cache.GetWeatherData(resolved.Latitude, resolved.Longitude, utils.Metric)
+	if err != nil {
+		return nil, err
+	}
+	if weatherData.Error {
+		return nil, fmt.Errorf("weather API error: %s", weatherData.Reason)
+	}
+
+	return &weather.TemperatureReading{
+		Temperature: weatherData.Current.Temperature,
+		Location:    location,
+		Timestamp:   time.Now().Unix(),
+		Unit:        "celsius",
+	}, nil
+}
 
 func (h *WeatherMonitorHandler) GetTemperature(ctx context.Context, req *weather.WeatherRequest) (*weather.TemperatureReading, error) {
-	log.Printf("Getting temperature for location: %s\n", req.Location)
-	
-	latitude, longitude := utils.GetCoordinatesForLocation(req.Location)
-	log.Printf("Using coordinates: lat=%.6f, lon=%.6f\n", latitude, longitude)
-	
-	weatherData, err := utils.GetWeatherData(latitude, longitude)
+	start := time.Now()
+	h.logger.WithContext(ctx).Info("getting temperature", "location", req.Location)
+
+	resolved, err := utils.ResolveLocation(h.geocoder, req.Location)
+	if err != nil {
+		var notFound *utils.LocationNotFoundError
+		if errors.As(err, &notFound) {
+			return nil, &weather.LocationNotFoundError{Query: notFound.Query}
+		}
+		return nil, &weather.WeatherServiceError{
+			Message: "Failed to resolve location",
+			Details: err.Error(),
+		}
+	}
+
+	weatherData, err := h.cache.GetWeatherData(resolved.Latitude, resolved.Longitude, utils.Metric)
 	if err != nil {
 		return nil, &weather.WeatherServiceError{
 			Message: "Failed to get weather data",
@@ -28,25 +109,266 @@ func (h *WeatherMonitorHandler) GetTemperature(ctx context.Context, req *weather
 	}
 
 	if weatherData.Error {
-		log.Printf("API returned error: %s\n", weatherData.Reason)
+		h.logger.WithContext(ctx).Error("upstream weather API error", "location", req.Location, "reason", weatherData.Reason)
 		return nil, &weather.WeatherServiceError{
 			Message: "Weather API error",
 			Details: weatherData.Reason,
 		}
 	}
 
-	log.Printf("Received temperature: %.2f°C\n", weatherData.Current.Temperature)
+	h.logger.WithContext(ctx).Info("temperature resolved", "location", req.Location, "temperature_c", weatherData.Current.Temperature, "latency_ms", time.Since(start).Milliseconds())
 
 	reading := &weather.TemperatureReading{
 		Temperature: weatherData.Current.Temperature,
-		Location:   req.Location,
-		Timestamp:  time.Now().Unix(),
-		Unit:       "celsius",
+		Location:    req.Location,
+		Timestamp:   time.Now().Unix(),
+		Unit:        "celsius",
 	}
 
 	return reading, nil
 }
 
+// ResolveLocation returns geocoding candidates for a free-form location
+// query, ranked by confidence.
+func (h *WeatherMonitorHandler) ResolveLocation(ctx context.Context, query string) ([]*weather.Candidate, error) {
+	h.logger.WithContext(ctx).Info("resolving location", "query", query)
+
+	matches, err := h.geocoder.Resolve(query)
+	if err != nil {
+		return nil, &weather.WeatherServiceError{
+			Message: "Failed to resolve location",
+			Details: err.Error(),
+		}
+	}
+
+	candidates := make([]*weather.Candidate, 0, len(matches))
+	for _, match := range matches {
+		candidates = append(candidates, &weather.Candidate{
+			Name:       match.Location.Name,
+			Latitude:   match.Location.Latitude,
+			Longitude:  match.Location.Longitude,
+			Country:    match.Location.Country,
+			Timezone:   match.Location.Timezone,
+			Elevation:  match.Location.Elevation,
+			Confidence: match.Confidence,
+		})
+	}
+
+	return candidates, nil
+}
+
+// toUnits maps the Thrift UnitSystem enum onto utils.Units.
+func toUnits(unit weather.UnitSystem) utils.Units {
+	if unit == weather.UnitSystem_IMPERIAL {
+		return utils.Imperial
+	}
+	return utils.Metric
+}
+
+// buildWeatherReading assembles a weather.WeatherReading from the current
+// conditions and hourly forecast returned by the provider, capped to the
+// requested number of forecast hours.
+func buildWeatherReading(location string, data *utils.OpenMeteoResponse, unit weather.UnitSystem, forecastHours int) *weather.WeatherReading {
+	reading := &weather.WeatherReading{
+		Location:  location,
+		Timestamp: time.Now().Unix(),
+		Unit:      unit,
+		Main: &weather.Main{
+			Temperature: data.Current.Temperature,
+			FeelsLike:   data.Current.FeelsLike,
+			Humidity:    int32(data.Current.Humidity),
+			Pressure:    data.Current.Pressure,
+		},
+		Wind: &weather.Wind{
+			Speed:     data.Current.WindSpeed,
+			Direction: data.Current.WindDirection,
+		},
+		Clouds: &weather.Clouds{
+			Coverage: int32(data.Current.CloudCover),
+		},
+		Precipitation: &weather.Precipitation{
+			Amount: data.Current.Precipitation,
+		},
+		Forecast: buildForecast(data, forecastHours),
+	}
+
+	return reading
+}
+
+// buildForecast converts the parallel hourly arrays from Open-Meteo into a
+// list of ForecastHour structs, capped to hours entries.
+func buildForecast(data *utils.OpenMeteoResponse, hours int) []*weather.ForecastHour {
+	count := len(data.Hourly.Time)
+	if hours > 0 && hours < count {
+		count = hours
+	}
+
+	forecast := make([]*weather.ForecastHour, 0, count)
+	for i := 0; i < count; i++ {
+		timestamp, err := time.Parse("2006-01-02T15:04", data.Hourly.Time[i])
+		if err != nil {
+			log.Printf("failed to parse forecast timestamp %q: %v\n", data.Hourly.Time[i], err)
+			continue
+		}
+
+		forecast = append(forecast, &weather.ForecastHour{
+			Timestamp: timestamp.Unix(),
+			Main: &weather.Main{
+				Temperature: data.Hourly.Temperature[i],
+				FeelsLike:   data.Hourly.FeelsLike[i],
+				Humidity:    int32(data.Hourly.Humidity[i]),
+				Pressure:    data.Hourly.Pressure[i],
+			},
+			Wind: &weather.Wind{
+				Speed:     data.Hourly.WindSpeed[i],
+				Direction: data.Hourly.WindDirection[i],
+			},
+			Clouds: &weather.Clouds{
+				Coverage: int32(data.Hourly.CloudCover[i]),
+			},
+			Precipitation: &weather.Precipitation{
+				Amount: data.Hourly.Precipitation[i],
+			},
+		})
+	}
+
+	return forecast
+}
+
+// GetWeatherReading returns the full current-conditions-plus-forecast
+// payload for a named location.
+func (h *WeatherMonitorHandler) GetWeatherReading(ctx context.Context, req *weather.WeatherRequest, unit weather.UnitSystem) (*weather.WeatherReading, error) {
+	h.logger.WithContext(ctx).Info("getting weather reading", "location", req.Location, "unit", unit)
+
+	resolved, err := utils.ResolveLocation(h.geocoder, req.Location)
+	if err != nil {
+		var notFound *utils.LocationNotFoundError
+		if errors.As(err, &notFound) {
+			return nil, &weather.LocationNotFoundError{Query: notFound.Query}
+		}
+		return nil, &weather.WeatherServiceError{
+			Message: "Failed to resolve location",
+			Details: err.Error(),
+		}
+	}
+
+	weatherData, err := h.cache.GetWeatherData(resolved.Latitude, resolved.Longitude, toUnits(unit))
+	if err != nil {
+		return nil, &weather.WeatherServiceError{
+			Message: "Failed to get weather data",
+			Details: err.Error(),
+		}
+	}
+	if weatherData.Error {
+		return nil, &weather.WeatherServiceError{
+			Message: "Weather API error",
+			Details: weatherData.Reason,
+		}
+	}
+
+	return buildWeatherReading(req.Location, weatherData, unit, 0), nil
+}
+
+// GetForecast returns up to hours hourly forecast entries for location.
+func (h *WeatherMonitorHandler) GetForecast(ctx context.Context, location string, hours int32, unit weather.UnitSystem) ([]*weather.ForecastHour, error) {
+	h.logger.WithContext(ctx).Info("getting forecast", "location", location, "hours", hours, "unit", unit)
+
+	resolved, err := utils.ResolveLocation(h.geocoder, location)
+	if err != nil {
+		var notFound *utils.LocationNotFoundError
+		if errors.As(err, &notFound) {
+			return nil, &weather.LocationNotFoundError{Query: notFound.Query}
+		}
+		return nil, &weather.WeatherServiceError{
+			Message: "Failed to resolve location",
+			Details: err.Error(),
+		}
+	}
+
+	weatherData, err := h.cache.GetWeatherData(resolved.Latitude, resolved.Longitude, toUnits(unit))
+	if err != nil {
+		return nil, &weather.WeatherServiceError{
+			Message: "Failed to get forecast data",
+			Details: err.Error(),
+		}
+	}
+	if weatherData.Error {
+		return nil, &weather.WeatherServiceError{
+			Message: "Weather API error",
+			Details: weatherData.Reason,
+		}
+	}
+
+	return buildForecast(weatherData, int(hours)), nil
+}
+
+// GetWeatherByCoordinates is GetWeatherReading for callers that already
+// have a resolved latitude/longitude.
+func (h *WeatherMonitorHandler) GetWeatherByCoordinates(ctx context.Context, req *weather.CoordinatesRequest) (*weather.WeatherReading, error) {
+	h.logger.WithContext(ctx).Info("getting weather by coordinates", "latitude", req.Latitude, "longitude", req.Longitude)
+
+	weatherData, err := h.cache.GetWeatherData(req.Latitude, req.Longitude, toUnits(req.Unit))
+	if err != nil {
+		return nil, &weather.WeatherServiceError{
+			Message: "Failed to get weather data",
+			Details: err.Error(),
+		}
+	}
+	if weatherData.Error {
+		return nil, &weather.WeatherServiceError{
+			Message: "Weather API error",
+			Details: weatherData.Reason,
+		}
+	}
+
+	location := fmt.Sprintf("%.4f,%.4f", req.Latitude, req.Longitude)
+	return buildWeatherReading(location, weatherData, req.Unit, 0), nil
+}
+
+// SubscribeTemperature starts a streaming subscription and returns the NATS
+// subject the caller should listen on for TemperatureReading frames.
+func (h *WeatherMonitorHandler) SubscribeTemperature(ctx context.Context, req *weather.StreamRequest) (*weather.SubscribeAck, error) {
+	h.logger.WithContext(ctx).Info("subscribing to temperature updates", "locations", req.Locations)
+
+	id, subject, err := h.subs.Subscribe(req)
+	if err != nil {
+		return nil, &weather.WeatherServiceError{
+			Message: "Failed to start subscription",
+			Details: err.Error(),
+		}
+	}
+
+	return &weather.SubscribeAck{
+		SubscriptionId: id,
+		Subject:        subject,
+	}, nil
+}
+
+// UnsubscribeTemperature stops the publisher goroutine behind a prior
+// SubscribeTemperature call.
+func (h *WeatherMonitorHandler) UnsubscribeTemperature(ctx context.Context, subscriptionId string) error {
+	h.logger.WithContext(ctx).Info("unsubscribing", "subscription_id", subscriptionId)
+
+	if err := h.subs.Unsubscribe(subscriptionId); err != nil {
+		return &weather.WeatherServiceError{
+			Message: "Failed to stop subscription",
+			Details: err.Error(),
+		}
+	}
+	return nil
+}
+
+// GetCacheStats reports the on-disk weather response cache's hit/miss/
+// error counters since server startup.
+func (h *WeatherMonitorHandler) GetCacheStats(ctx context.Context) (*weather.CacheStats, error) {
+	stats := h.cache.Stats()
+	return &weather.CacheStats{
+		Hits:   stats.Hits,
+		Misses: stats.Misses,
+		Errors: stats.Errors,
+	}, nil
+}
+
 func main() {
 	transportFactory := thrift.NewTFramedTransportFactoryConf(thrift.NewTTransportFactory(), nil)
 	protocolFactory := thrift.NewTBinaryProtocolFactoryConf(nil)
@@ -56,18 +378,110 @@ func main() {
 		log.Fatalf("Failed to create server socket: %v", err)
 	}
 
-	handler := &WeatherMonitorHandler{}
+	alias := os.Getenv("WEATHER_SERVER_ALIAS")
+	if alias == "" {
+		alias = "weather-server"
+	}
+	logger := NewLogger(alias)
+	metrics := NewMetrics()
+
+	natsURL := os.Getenv("NATS_URL")
+	if natsURL == "" {
+		natsURL = nats.DefaultURL
+	}
+	natsConn, err := nats.Connect(natsURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to NATS: %v", err)
+	}
+	defer natsConn.Close()
+
+	geocoder, err := utils.NewGeocodeCache(utils.GeocodeCacheConfig{
+		CacheLocation: defaultGeocodeCacheLocation,
+		LRUSize:       defaultGeocodeLRUSize,
+	}, utils.NewOpenMeteoGeocoder())
+	if err != nil {
+		log.Fatalf("Failed to initialize geocoder: %v", err)
+	}
+
+	provider, err := utils.NewProviderFromEnv(geocoder)
+	if err != nil {
+		log.Fatalf("Failed to select weather provider: %v", err)
+	}
+
+	maxSubscriptions := maxStreamSubscriptions
+	if raw := os.Getenv("MAX_STREAM_SUBSCRIPTIONS"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			log.Fatalf("Invalid MAX_STREAM_SUBSCRIPTIONS %q: %v", raw, err)
+		}
+		maxSubscriptions = parsed
+	}
+
+	cacheLocation := os.Getenv("WEATHER_CACHE_LOCATION")
+	if cacheLocation == "" {
+		cacheLocation = defaultCacheLocation
+	}
+	cacheMaxAge := defaultCacheMaxAge
+	if raw := os.Getenv("WEATHER_CACHE_MAX_AGE"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil {
+			log.Fatalf("Invalid WEATHER_CACHE_MAX_AGE %q: %v", raw, err)
+		}
+		cacheMaxAge = parsed
+	}
+	cacheConfig := utils.CacheConfig{CacheLocation: cacheLocation, MaxAge: cacheMaxAge}
+
+	handler := NewWeatherMonitorHandler(natsConn, protocolFactory, provider, geocoder, alias, maxSubscriptions, cacheConfig)
+	defer handler.subs.Shutdown()
+
 	processor := weather.NewWeatherMonitorServiceProcessor(handler)
+	instrumented := NewInstrumentedProcessor(processor, metrics, logger)
+
+	metricsAddr := os.Getenv("METRICS_ADDR")
+	if metricsAddr == "" {
+		metricsAddr = ":9100"
+	}
+	go func() {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics)
+		if err := http.ListenAndServe(metricsAddr, mux); err != nil {
+			log.Printf("metrics server stopped: %v", err)
+		}
+	}()
+
+	// WEATHER_TRANSPORT selects how the WeatherMonitorService processor is
+	// served: "tcp" (default) runs the regular TSimpleServer4 socket loop;
+	// "nats" serves the same processor over a NATS request/reply subject
+	// instead, for deployments that want a single NATS connection for both
+	// RPC and streaming.
+	transport := os.Getenv("WEATHER_TRANSPORT")
+	if transport == "" {
+		transport = "tcp"
+	}
+
+	switch transport {
+	case "nats":
+		sub, err := NewTNatsServerTransport(natsConn, instrumented, protocolFactory)
+		if err != nil {
+			log.Fatalf("Failed to start NATS transport: %v", err)
+		}
+		defer sub.Unsubscribe()
 
-	server := thrift.NewTSimpleServer4(
-		processor,
-		serverTransport,
-		transportFactory,
-		protocolFactory,
-	)
+		fmt.Printf("Starting Weather Monitor Server on NATS subject %q (alias=%s, metrics=%s)...\n", natsRequestSubject, alias, metricsAddr)
+		select {}
+	case "tcp":
+		server := thrift.NewTSimpleServer4(
+			instrumented,
+			serverTransport,
+			transportFactory,
+			protocolFactory,
+		)
 
-	fmt.Println("Starting Weather Monitor Server on :9091...")
-	if err := server.Serve(); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+		fmt.Printf("Starting Weather Monitor Server on :9091 (alias=%s, metrics=%s)...\n", alias, metricsAddr)
+		if err := server.Serve(); err != nil {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	default:
+		log.Fatalf("Unknown WEATHER_TRANSPORT %q: must be \"tcp\" or \"nats\"", transport)
 	}
-} 
\ No newline at end of file
+}
@@ -3,13 +3,22 @@ package main
 import (
 	"context"
 	"testing"
+	"time"
 
+	utils "github.com/example/weather-stream-server/utils"
 	"github.com/example/weather-stream-server/weather"
 )
 
 func TestGetTemperature(t *testing.T) {
-	handler := &WeatherMonitorHandler{}
-	
+	cache, err := utils.NewCache(utils.CacheConfig{
+		CacheLocation: t.TempDir(),
+		MaxAge:        time.Minute,
+	}, utils.NewMockProvider())
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	handler := &WeatherMonitorHandler{cache: cache, geocoder: utils.NewMockGeocoder(), logger: NewLogger("test")}
+
 	testCases := []struct {
 		name     string
 		location string
@@ -26,9 +35,9 @@ func TestGetTemperature(t *testing.T) {
 			wantErr:  false,
 		},
 		{
-			name:     "Unknown location - defaults to New York",
+			name:     "Unknown location - returns LocationNotFoundError",
 			location: "unknown_city",
-			wantErr:  false,
+			wantErr:  true,
 		},
 	}
 
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+
+	"github.com/example/weather-stream-server/weather"
+)
+
+// defaultStreamInterval is used when a StreamRequest does not specify one.
+const defaultStreamInterval = 30 * time.Second
+
+// SubscriptionManager tracks the goroutine-per-subscription publishers that
+// back SubscribeTemperature. Each subscription polls fetch() for every
+// requested location on its own ticker and publishes the readings to a
+// dedicated NATS subject until it is cancelled or the manager is shut down.
+type SubscriptionManager struct {
+	conn            *nats.Conn
+	protocolFactory thrift.TProtocolFactory
+	fetch           func(location string) (*weather.TemperatureReading, error)
+	maxClients      int
+
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewSubscriptionManager wires a SubscriptionManager to the NATS connection
+// used to fan readings out to subscribers and the fetch function used to
+// produce each reading (normally WeatherMonitorHandler's own lookup path).
+func NewSubscriptionManager(conn *nats.Conn, protocolFactory thrift.TProtocolFactory, maxClients int, fetch func(location string) (*weather.TemperatureReading, error)) *SubscriptionManager {
+	return &SubscriptionManager{
+		conn:            conn,
+		protocolFactory: protocolFactory,
+		fetch:           fetch,
+		maxClients:      maxClients,
+		cancels:         make(map[string]context.CancelFunc),
+	}
+}
+
+// Subscribe starts a new publisher goroutine and returns its subscription
+// ID and the NATS subject readings will be published on.
+func (m *SubscriptionManager) Subscribe(req *weather.StreamRequest) (string, string, error) {
+	if len(req.Locations) == 0 {
+		return "", "", fmt.Errorf("at least one location is required")
+	}
+
+	m.mu.Lock()
+	if m.maxClients > 0 && len(m.cancels) >= m.maxClients {
+		m.mu.Unlock()
+		return "", "", fmt.Errorf("subscription limit reached: %d", m.maxClients)
+	}
+
+	id := uuid.NewString()
+	subject := fmt.Sprintf("weather.stream.%s", id)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancels[id] = cancel
+	m.mu.Unlock()
+
+	interval := defaultStreamInterval
+	if req.IntervalSeconds > 0 {
+		interval = time.Duration(req.IntervalSeconds) * time.Second
+	}
+
+	m.wg.Add(1)
+	go m.publish(ctx, id, subject, req.Locations, interval)
+
+	return id, subject, nil
+}
+
+// Unsubscribe cancels the publisher goroutine for id, if any is running.
+func (m *SubscriptionManager) Unsubscribe(id string) error {
+	m.mu.Lock()
+	cancel, ok := m.cancels[id]
+	if ok {
+		delete(m.cancels, id)
+	}
+	m.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("no such subscription: %s", id)
+	}
+	cancel()
+	return nil
+}
+
+// Shutdown cancels every active subscription and waits for their publisher
+// goroutines to exit.
+func (m *SubscriptionManager) Shutdown() {
+	m.mu.Lock()
+	for id, cancel := range m.cancels {
+		cancel()
+		delete(m.cancels, id)
+	}
+	m.mu.Unlock()
+
+	m.wg.Wait()
+}
+
+func (m *SubscriptionManager) publish(ctx context.Context, id, subject string, locations []string, interval time.Duration) {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	log.Printf("subscription %s: started for %v every %s\n", id, locations, interval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("subscription %s: stopped\n", id)
+			return
+		case <-ticker.C:
+			for _, location := range locations {
+				reading, err := m.fetch(location)
+				if err != nil {
+					log.Printf("subscription %s: fetch failed for %s: %v\n", id, location, err)
+					continue
+				}
+
+				err = PublishReading(m.conn, m.protocolFactory, subject, func(oprot thrift.TProtocol) error {
+					return reading.Write(ctx, oprot)
+				})
+				if err != nil {
+					log.Printf("subscription %s: publish failed for %s: %v\n", id, location, err)
+				}
+			}
+		}
+	}
+}
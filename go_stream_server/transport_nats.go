@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/nats-io/nats.go"
+)
+
+// natsRequestSubject is the subject clients publish Thrift requests to;
+// the server replies on the inbox carried in the NATS message.
+const natsRequestSubject = "weather.rpc"
+
+// NewTNatsServerTransport adapts a framed Thrift processor to be served
+// over a NATS request/reply subject instead of a raw TCP socket, mirroring
+// the regular TSimpleServer loop but pulling frames off NATS.
+func NewTNatsServerTransport(conn *nats.Conn, processor thrift.TProcessor, protocolFactory thrift.TProtocolFactory) (*nats.Subscription, error) {
+	return conn.Subscribe(natsRequestSubject, func(msg *nats.Msg) {
+		if msg.Reply == "" {
+			return
+		}
+
+		transport := thrift.NewTMemoryBuffer()
+		if _, err := transport.Write(msg.Data); err != nil {
+			return
+		}
+
+		iprot := protocolFactory.GetProtocol(transport)
+		reply := thrift.NewTMemoryBufferLen(len(msg.Data))
+		oprot := protocolFactory.GetProtocol(reply)
+
+		// Process writes a result or an exception into reply even when it
+		// reports an error, so whatever it wrote is sent back.
+		processor.Process(context.Background(), iprot, oprot)
+		if reply.Len() == 0 {
+			return
+		}
+
+		if err := conn.Publish(msg.Reply, reply.Bytes()); err != nil {
+			return
+		}
+	})
+}
+
+// PublishReading serializes a TemperatureReading with the given protocol
+// factory and publishes it on subject as one frame; a subscription's
+// publisher goroutine sends one frame per location on every tick.
+func PublishReading(conn *nats.Conn, protocolFactory thrift.TProtocolFactory, subject string, write func(thrift.TProtocol) error) error {
+	buf := thrift.NewTMemoryBuffer()
+	oprot := protocolFactory.GetProtocol(buf)
+
+	if err := write(oprot); err != nil {
+		return fmt.Errorf("failed to serialize reading: %v", err)
+	}
+	if err := oprot.Flush(context.Background()); err != nil {
+		return fmt.Errorf("failed to flush reading: %v", err)
+	}
+
+	return conn.Publish(subject, buf.Bytes())
+}
@@ -0,0 +1,159 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// errTooOld is returned by loadFromDisk when a cache entry exists but is
+// older than the configured MaxAge.
+var errTooOld = errors.New("cached response is too old")
+
+// CacheConfig controls where cached responses are kept and how long they
+// stay fresh before a new fetch is required.
+type CacheConfig struct {
+	CacheLocation string
+	MaxAge        time.Duration
+}
+
+// CacheStats is a snapshot of a Cache's hit/miss/error counters, exposed to
+// callers via GetCacheStats.
+type CacheStats struct {
+	Hits   int64
+	Misses int64
+	Errors int64
+}
+
+// Cache wraps a WeatherProvider with an on-disk, TTL'd cache keyed by
+// coordinates and unit system, so upstream rate limits or outages don't
+// break callers that can tolerate a slightly stale reading.
+type Cache struct {
+	config   CacheConfig
+	provider WeatherProvider
+
+	mu    sync.Mutex
+	stats CacheStats
+}
+
+// NewCache creates a Cache rooted at config.CacheLocation, creating the
+// directory if it does not already exist, fetching through provider on a
+// cache miss.
+func NewCache(config CacheConfig, provider WeatherProvider) (*Cache, error) {
+	if err := os.MkdirAll(config.CacheLocation, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %v", err)
+	}
+	return &Cache{config: config, provider: provider}, nil
+}
+
+// Stats returns a snapshot of the cache's hit/miss/error counters.
+func (c *Cache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// GetWeatherData serves latitude/longitude/units from the on-disk cache
+// when a fresh entry exists, otherwise fetches from the network and
+// refreshes the cache. A network error falls back to a stale cache entry,
+// if one is present, rather than failing the call outright.
+func (c *Cache) GetWeatherData(latitude, longitude float64, units Units) (*OpenMeteoResponse, error) {
+	key := c.cacheKey(latitude, longitude, units)
+
+	if cached, err := c.loadFromDisk(key, c.config.MaxAge); err == nil {
+		c.recordHit()
+		return cached, nil
+	} else if !errors.Is(err, errTooOld) && !os.IsNotExist(err) {
+		log.Printf("cache: failed to read %s: %v\n", key, err)
+	}
+
+	data, err := c.provider.CurrentByCoordinates(latitude, longitude, units)
+	if err != nil {
+		if stale, staleErr := c.loadFromDisk(key, 0); staleErr == nil {
+			log.Printf("cache: serving stale entry for %s after fetch error: %v\n", key, err)
+			c.recordHit()
+			return stale, nil
+		}
+		c.recordError()
+		return nil, err
+	}
+
+	c.recordMiss()
+	if saveErr := c.saveToDisk(key, data); saveErr != nil {
+		log.Printf("cache: failed to save %s: %v\n", key, saveErr)
+	}
+
+	return data, nil
+}
+
+func (c *Cache) recordHit() {
+	c.mu.Lock()
+	c.stats.Hits++
+	c.mu.Unlock()
+}
+
+func (c *Cache) recordMiss() {
+	c.mu.Lock()
+	c.stats.Misses++
+	c.mu.Unlock()
+}
+
+func (c *Cache) recordError() {
+	c.mu.Lock()
+	c.stats.Errors++
+	c.mu.Unlock()
+}
+
+// cacheKey derives a filesystem-safe file name from the active provider,
+// the requested coordinates, and the unit system, so switching
+// WeatherProvider between restarts can't serve one provider's cached
+// response shape under another's name.
+func (c *Cache) cacheKey(latitude, longitude float64, units Units) string {
+	key := fmt.Sprintf("%s,%.4f,%.4f,%s", c.provider.Name(), latitude, longitude, units)
+	key = strings.ReplaceAll(key, ",", "_")
+	return key + ".json"
+}
+
+// loadFromDisk reads and decodes the cache entry for key, returning
+// errTooOld if its age exceeds maxAge. A maxAge of 0 disables the age
+// check, returning whatever is on disk regardless of staleness.
+func (c *Cache) loadFromDisk(key string, maxAge time.Duration) (*OpenMeteoResponse, error) {
+	path := filepath.Join(c.config.CacheLocation, key)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if maxAge > 0 && time.Since(info.ModTime()) > maxAge {
+		return nil, errTooOld
+	}
+
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var data OpenMeteoResponse
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("failed to decode cached response: %v", err)
+	}
+
+	return &data, nil
+}
+
+// saveToDisk persists data under key after a successful fetch.
+func (c *Cache) saveToDisk(key string, data *OpenMeteoResponse) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode response for cache: %v", err)
+	}
+
+	path := filepath.Join(c.config.CacheLocation, key)
+	return os.WriteFile(path, body, 0o644)
+}
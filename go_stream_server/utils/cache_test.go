@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// stubProvider is a WeatherProvider whose CurrentByCoordinates result and
+// error are set directly by the test, so cache behavior can be exercised
+// without a live upstream.
+type stubProvider struct {
+	reading *OpenMeteoResponse
+	err     error
+	calls   int
+}
+
+func (p *stubProvider) CurrentByLocation(location string, units Units) (*OpenMeteoResponse, error) {
+	return p.CurrentByCoordinates(0, 0, units)
+}
+
+func (p *stubProvider) CurrentByCoordinates(latitude, longitude float64, units Units) (*OpenMeteoResponse, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return p.reading, nil
+}
+
+func (p *stubProvider) ForecastByCoordinates(latitude, longitude float64, hours int, units Units) (*OpenMeteoResponse, error) {
+	return p.CurrentByCoordinates(latitude, longitude, units)
+}
+
+func (p *stubProvider) Name() string {
+	return "stub"
+}
+
+func TestCacheLoadFromDisk_TTLBoundary(t *testing.T) {
+	cache, err := NewCache(CacheConfig{CacheLocation: t.TempDir(), MaxAge: time.Hour}, &stubProvider{})
+	if err != nil {
+		t.Fatalf("NewCache failed: %v", err)
+	}
+
+	key := cache.cacheKey(51.5074, -0.1278, Metric)
+	reading := &OpenMeteoResponse{}
+	reading.Current.Temperature = 10
+	if err := cache.saveToDisk(key, reading); err != nil {
+		t.Fatalf("saveToDisk failed: %v", err)
+	}
+
+	if _, err := cache.loadFromDisk(key, time.Hour); err != nil {
+		t.Fatalf("expected fresh entry to load, got: %v", err)
+	}
+
+	path := filepath.Join(cache.config.CacheLocation, key)
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatalf("Chtimes failed: %v", err)
+	}
+
+	if _, err := cache.loadFromDisk(key, time.Hour); !errors.Is(err, errTooOld) {
+		t.Fatalf("expected errTooOld for stale entry, got: %v", err)
+	}
+
+	if _, err := cache.loadFromDisk(key, 0); err != nil {
+		t.Fatalf("maxAge 0 should disable the age check, got: %v", err)
+	}
+}
+
+func TestCacheGetWeatherData_StaleFallbackOnFetchError(t *testing.T) {
+	reading := &OpenMeteoResponse{}
+	reading.Current.Temperature = 21.5
+
+	provider := &stubProvider{reading: reading}
+	cache, err := NewCache(CacheConfig{CacheLocation: t.TempDir(), MaxAge: time.Millisecond}, provider)
+	if err != nil {
+		t.Fatalf("NewCache failed: %v", err)
+	}
+
+	first, err := cache.GetWeatherData(51.5074, -0.1278, Metric)
+	if err != nil {
+		t.Fatalf("initial fetch failed: %v", err)
+	}
+	if first.Current.Temperature != reading.Current.Temperature {
+		t.Fatalf("expected temperature %v, got %v", reading.Current.Temperature, first.Current.Temperature)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	provider.err = fmt.Errorf("upstream unavailable")
+
+	stale, err := cache.GetWeatherData(51.5074, -0.1278, Metric)
+	if err != nil {
+		t.Fatalf("expected stale fallback instead of error, got: %v", err)
+	}
+	if stale.Current.Temperature != reading.Current.Temperature {
+		t.Fatalf("expected stale entry's temperature %v, got %v", reading.Current.Temperature, stale.Current.Temperature)
+	}
+
+	stats := cache.Stats()
+	if stats.Errors != 0 {
+		t.Fatalf("expected no recorded errors when a stale entry is available, got %d", stats.Errors)
+	}
+}
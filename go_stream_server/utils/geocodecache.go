@@ -0,0 +1,152 @@
+package utils
+
+import (
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// GeocodeCacheConfig controls the in-memory LRU size and on-disk cache
+// directory for a GeocodeCache.
+type GeocodeCacheConfig struct {
+	CacheLocation string
+	LRUSize       int
+}
+
+type geocodeCacheEntry struct {
+	key     string
+	matches []GeocodeMatch
+}
+
+// GeocodeCache wraps a Geocoder with an in-memory LRU plus an on-disk
+// cache, so repeated lookups of the same location don't re-hit the
+// geocoding API.
+type GeocodeCache struct {
+	geocoder Geocoder
+	config   GeocodeCacheConfig
+
+	mu    sync.Mutex
+	lru   *list.List
+	index map[string]*list.Element
+}
+
+// NewGeocodeCache creates a GeocodeCache rooted at config.CacheLocation,
+// creating the directory if it does not already exist.
+func NewGeocodeCache(config GeocodeCacheConfig, geocoder Geocoder) (*GeocodeCache, error) {
+	if err := os.MkdirAll(config.CacheLocation, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create geocode cache directory: %v", err)
+	}
+	if config.LRUSize <= 0 {
+		config.LRUSize = 100
+	}
+
+	return &GeocodeCache{
+		geocoder: geocoder,
+		config:   config,
+		lru:      list.New(),
+		index:    make(map[string]*list.Element),
+	}, nil
+}
+
+// Resolve serves query from the in-memory LRU, then the on-disk cache,
+// falling back to the wrapped Geocoder and populating both caches on a
+// miss.
+func (c *GeocodeCache) Resolve(query string) ([]GeocodeMatch, error) {
+	key := normalizeQuery(query)
+
+	if matches, ok := c.fromLRU(key); ok {
+		return matches, nil
+	}
+
+	if matches, ok := c.fromDisk(key); ok {
+		c.storeLRU(key, matches)
+		return matches, nil
+	}
+
+	matches, err := c.geocoder.Resolve(query)
+	if err != nil {
+		return nil, err
+	}
+
+	c.storeLRU(key, matches)
+	if err := c.saveToDisk(key, matches); err != nil {
+		log.Printf("geocode cache: failed to save %s: %v\n", key, err)
+	}
+
+	return matches, nil
+}
+
+func (c *GeocodeCache) fromLRU(key string) ([]GeocodeMatch, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.index[key]
+	if !ok {
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+	return elem.Value.(*geocodeCacheEntry).matches, true
+}
+
+func (c *GeocodeCache) storeLRU(key string, matches []GeocodeMatch) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.index[key]; ok {
+		elem.Value.(*geocodeCacheEntry).matches = matches
+		c.lru.MoveToFront(elem)
+		return
+	}
+
+	elem := c.lru.PushFront(&geocodeCacheEntry{key: key, matches: matches})
+	c.index[key] = elem
+
+	if c.lru.Len() > c.config.LRUSize {
+		oldest := c.lru.Back()
+		if oldest != nil {
+			c.lru.Remove(oldest)
+			delete(c.index, oldest.Value.(*geocodeCacheEntry).key)
+		}
+	}
+}
+
+func (c *GeocodeCache) fromDisk(key string) ([]GeocodeMatch, bool) {
+	body, err := os.ReadFile(c.diskPath(key))
+	if err != nil {
+		return nil, false
+	}
+
+	var matches []GeocodeMatch
+	if err := json.Unmarshal(body, &matches); err != nil {
+		log.Printf("geocode cache: failed to decode %s: %v\n", key, err)
+		return nil, false
+	}
+
+	return matches, true
+}
+
+func (c *GeocodeCache) saveToDisk(key string, matches []GeocodeMatch) error {
+	body, err := json.Marshal(matches)
+	if err != nil {
+		return fmt.Errorf("failed to encode geocode result: %v", err)
+	}
+	return os.WriteFile(c.diskPath(key), body, 0o644)
+}
+
+func (c *GeocodeCache) diskPath(key string) string {
+	return filepath.Join(c.config.CacheLocation, key+".json")
+}
+
+// normalizeQuery derives a filesystem- and map-safe cache key from a
+// free-form location query.
+func normalizeQuery(query string) string {
+	key := strings.ToLower(strings.TrimSpace(query))
+	key = strings.ReplaceAll(key, " ", "_")
+	key = strings.ReplaceAll(key, "/", "_")
+	return key
+}
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"container/list"
+	"testing"
+)
+
+func newTestGeocodeCache(lruSize int) *GeocodeCache {
+	return &GeocodeCache{
+		config: GeocodeCacheConfig{LRUSize: lruSize},
+		lru:    list.New(),
+		index:  make(map[string]*list.Element),
+	}
+}
+
+func storeTestMatch(c *GeocodeCache, key string) {
+	c.storeLRU(key, []GeocodeMatch{{Location: GeoLocation{Name: key}, Confidence: 1}})
+}
+
+func TestGeocodeCacheLRU_EvictsOldestOnOverflow(t *testing.T) {
+	c := newTestGeocodeCache(2)
+
+	storeTestMatch(c, "london")
+	storeTestMatch(c, "tokyo")
+	storeTestMatch(c, "paris")
+
+	if _, ok := c.fromLRU("london"); ok {
+		t.Error("expected least recently used entry \"london\" to be evicted")
+	}
+	if _, ok := c.fromLRU("tokyo"); !ok {
+		t.Error("expected \"tokyo\" to still be cached")
+	}
+	if _, ok := c.fromLRU("paris"); !ok {
+		t.Error("expected \"paris\" to still be cached")
+	}
+}
+
+func TestGeocodeCacheLRU_AccessProtectsFromEviction(t *testing.T) {
+	c := newTestGeocodeCache(2)
+
+	storeTestMatch(c, "london")
+	storeTestMatch(c, "tokyo")
+
+	if _, ok := c.fromLRU("london"); !ok {
+		t.Fatal("expected \"london\" to be cached before it is touched")
+	}
+
+	storeTestMatch(c, "paris")
+
+	if _, ok := c.fromLRU("tokyo"); ok {
+		t.Error("expected \"tokyo\" to be evicted as the least recently used entry")
+	}
+	if _, ok := c.fromLRU("london"); !ok {
+		t.Error("expected \"london\" to survive eviction after being accessed")
+	}
+	if _, ok := c.fromLRU("paris"); !ok {
+		t.Error("expected \"paris\" to still be cached")
+	}
+}
@@ -0,0 +1,184 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// minConfidence is the default threshold a geocoding candidate must clear
+// to be accepted instead of returning LocationNotFoundError.
+const minConfidence = 0.45
+
+// GeoLocation is a resolved geocoding candidate: coordinates plus the
+// metadata needed to disambiguate same-named places.
+type GeoLocation struct {
+	Name      string
+	Latitude  float64
+	Longitude float64
+	Country   string
+	Timezone  string
+	Elevation float64
+}
+
+// GeocodeMatch pairs a GeoLocation with how well it matched the query, in
+// descending order of Confidence.
+type GeocodeMatch struct {
+	Location   GeoLocation
+	Confidence float64
+}
+
+// LocationNotFoundError is returned when no geocoding candidate clears
+// minConfidence for a query.
+type LocationNotFoundError struct {
+	Query string
+}
+
+func (e *LocationNotFoundError) Error() string {
+	return fmt.Sprintf("no location found for query: %q", e.Query)
+}
+
+// Geocoder resolves a free-form location string into candidate
+// coordinates, ranked by confidence.
+type Geocoder interface {
+	Resolve(query string) ([]GeocodeMatch, error)
+}
+
+// ResolveLocation picks the highest-confidence match for query from
+// geocoder, returning *LocationNotFoundError if nothing clears
+// minConfidence.
+func ResolveLocation(geocoder Geocoder, query string) (*GeoLocation, error) {
+	matches, err := geocoder.Resolve(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 || matches[0].Confidence < minConfidence {
+		return nil, &LocationNotFoundError{Query: query}
+	}
+	return &matches[0].Location, nil
+}
+
+// OpenMeteoGeocoder resolves locations via Open-Meteo's free geocoding API.
+type OpenMeteoGeocoder struct{}
+
+// NewOpenMeteoGeocoder returns a Geocoder backed by Open-Meteo's geocoding
+// API.
+func NewOpenMeteoGeocoder() *OpenMeteoGeocoder {
+	return &OpenMeteoGeocoder{}
+}
+
+type openMeteoGeocodeResponse struct {
+	Results []struct {
+		Name      string  `json:"name"`
+		Latitude  float64 `json:"latitude"`
+		Longitude float64 `json:"longitude"`
+		Country   string  `json:"country"`
+		Timezone  string  `json:"timezone"`
+		Elevation float64 `json:"elevation"`
+	} `json:"results"`
+}
+
+func (g *OpenMeteoGeocoder) Resolve(query string) ([]GeocodeMatch, error) {
+	requestURL := fmt.Sprintf("https://geocoding-api.open-meteo.com/v1/search?name=%s&count=5", url.QueryEscape(query))
+	log.Printf("Making request to: %s\n", requestURL)
+
+	resp, err := http.Get(requestURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch geocoding data: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read geocoding response: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("geocoding API error: status code %d", resp.StatusCode)
+	}
+
+	var parsed openMeteoGeocodeResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode geocoding response: %v", err)
+	}
+
+	matches := make([]GeocodeMatch, 0, len(parsed.Results))
+	for _, result := range parsed.Results {
+		matches = append(matches, GeocodeMatch{
+			Location: GeoLocation{
+				Name:      result.Name,
+				Latitude:  result.Latitude,
+				Longitude: result.Longitude,
+				Country:   result.Country,
+				Timezone:  result.Timezone,
+				Elevation: result.Elevation,
+			},
+			Confidence: fuzzyScore(query, result.Name),
+		})
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Confidence > matches[j].Confidence
+	})
+
+	return matches, nil
+}
+
+// fuzzyScore scores how closely candidate matches query, in [0, 1], using
+// normalized Levenshtein distance over the lowercased strings.
+func fuzzyScore(query, candidate string) float64 {
+	a := strings.ToLower(strings.TrimSpace(query))
+	b := strings.ToLower(strings.TrimSpace(candidate))
+
+	if a == b {
+		return 1
+	}
+
+	maxLen := math.Max(float64(len(a)), float64(len(b)))
+	if maxLen == 0 {
+		return 0
+	}
+
+	return 1 - float64(levenshtein(a, b))/maxLen
+}
+
+// levenshtein computes the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min3(curr[j-1]+1, prev[j]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	testCases := []struct {
+		name      string
+		query     string
+		candidate string
+		wantMatch bool
+	}{
+		{name: "exact match", query: "London", candidate: "London", wantMatch: true},
+		{name: "typo clears threshold", query: "Londn", candidate: "London", wantMatch: true},
+		{name: "different case and spacing", query: " paris ", candidate: "Paris", wantMatch: true},
+		{name: "unrelated city falls below threshold", query: "Tokyo", candidate: "Osaka", wantMatch: false},
+		{name: "empty query falls below threshold", query: "", candidate: "London", wantMatch: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			score := fuzzyScore(tc.query, tc.candidate)
+			if got := score >= minConfidence; got != tc.wantMatch {
+				t.Errorf("fuzzyScore(%q, %q) = %v, clears minConfidence = %v, want %v", tc.query, tc.candidate, score, got, tc.wantMatch)
+			}
+		})
+	}
+}
+
+// stubGeocoder returns a single canned match at a fixed confidence, so
+// ResolveLocation's minConfidence cutoff can be pinned down without the
+// fuzzy-matching logic in the way.
+type stubGeocoder struct {
+	confidence float64
+}
+
+func (g *stubGeocoder) Resolve(query string) ([]GeocodeMatch, error) {
+	return []GeocodeMatch{{
+		Location:   GeoLocation{Name: query},
+		Confidence: g.confidence,
+	}}, nil
+}
+
+func TestResolveLocation_ConfidenceThreshold(t *testing.T) {
+	testCases := []struct {
+		name       string
+		confidence float64
+		wantErr    bool
+	}{
+		{name: "well above threshold", confidence: 1.0, wantErr: false},
+		{name: "exactly at threshold", confidence: minConfidence, wantErr: false},
+		{name: "just below threshold", confidence: minConfidence - 0.01, wantErr: true},
+		{name: "no confidence at all", confidence: 0, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ResolveLocation(&stubGeocoder{confidence: tc.confidence}, "some query")
+
+			var notFound *LocationNotFoundError
+			if tc.wantErr {
+				if !errors.As(err, &notFound) {
+					t.Fatalf("expected a LocationNotFoundError, got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
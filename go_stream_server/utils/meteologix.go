@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+)
+
+// meteologixResponse mirrors the station-reading shape used by
+// Meteologix-style weather APIs: a flat "data" object per location rather
+// than Open-Meteo's "current"/"hourly" split.
+type meteologixResponse struct {
+	Data struct {
+		Temperature   float64 `json:"temperature"`
+		FeelsLike     float64 `json:"feelsLike"`
+		Humidity      int     `json:"humidity"`
+		Pressure      float64 `json:"pressure"`
+		Precipitation float64 `json:"precipitation"`
+		Wind          struct {
+			Speed     float64 `json:"speed"`
+			Direction float64 `json:"direction"`
+		} `json:"wind"`
+		Clouds struct {
+			Coverage int `json:"coverage"`
+		} `json:"clouds"`
+	} `json:"data"`
+	Error string `json:"error"`
+}
+
+// MeteologixProvider is a WeatherProvider backed by a Meteologix-style
+// station API.
+type MeteologixProvider struct {
+	apiKey   string
+	geocoder Geocoder
+}
+
+// NewMeteologixProvider returns a WeatherProvider backed by a
+// Meteologix-style API, authenticated with apiKey and resolving location
+// names to coordinates via geocoder.
+func NewMeteologixProvider(apiKey string, geocoder Geocoder) *MeteologixProvider {
+	return &MeteologixProvider{apiKey: apiKey, geocoder: geocoder}
+}
+
+func (p *MeteologixProvider) CurrentByLocation(location string, units Units) (*OpenMeteoResponse, error) {
+	resolved, err := ResolveLocation(p.geocoder, location)
+	if err != nil {
+		return nil, err
+	}
+	return p.CurrentByCoordinates(resolved.Latitude, resolved.Longitude, units)
+}
+
+func (p *MeteologixProvider) CurrentByCoordinates(latitude, longitude float64, units Units) (*OpenMeteoResponse, error) {
+	url := fmt.Sprintf(
+		"https://api.meteologix.com/v1/observations?lat=%.6f&lon=%.6f&units=%s&apiKey=%s",
+		latitude, longitude, string(units), p.apiKey)
+
+	raw, err := meteologixGet(url)
+	if err != nil {
+		return nil, err
+	}
+	if raw.Error != "" {
+		return nil, fmt.Errorf("meteologix error: %s", raw.Error)
+	}
+
+	data := &OpenMeteoResponse{}
+	data.Current.Temperature = raw.Data.Temperature
+	data.Current.FeelsLike = raw.Data.FeelsLike
+	data.Current.Humidity = raw.Data.Humidity
+	data.Current.Pressure = raw.Data.Pressure
+	data.Current.WindSpeed = raw.Data.Wind.Speed
+	data.Current.WindDirection = raw.Data.Wind.Direction
+	data.Current.CloudCover = raw.Data.Clouds.Coverage
+	data.Current.Precipitation = raw.Data.Precipitation
+
+	return data, nil
+}
+
+func (p *MeteologixProvider) ForecastByCoordinates(latitude, longitude float64, hours int, units Units) (*OpenMeteoResponse, error) {
+	return nil, fmt.Errorf("meteologix provider does not support hourly forecasts")
+}
+
+func (p *MeteologixProvider) Name() string {
+	return "meteologix"
+}
+
+func meteologixGet(url string) (*meteologixResponse, error) {
+	log.Printf("Making request to: %s\n", redactAPIKeyFromURL(url))
+
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch weather data: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("weather API error: status code %d", resp.StatusCode)
+	}
+
+	var raw meteologixResponse
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("failed to decode weather data: %v", err)
+	}
+
+	return &raw, nil
+}
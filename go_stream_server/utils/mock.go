@@ -0,0 +1,77 @@
+package utils
+
+import "strings"
+
+// MockProvider is a WeatherProvider that returns fixed fixtures instead of
+// calling a live API, so callers like TestGetTemperature don't depend on
+// network access.
+type MockProvider struct {
+	Reading *OpenMeteoResponse
+}
+
+// NewMockProvider returns a MockProvider seeded with a plausible fixture
+// reading. Override Reading to exercise other cases.
+func NewMockProvider() *MockProvider {
+	reading := &OpenMeteoResponse{}
+	reading.Current.Temperature = 18.5
+	reading.Current.FeelsLike = 17.9
+	reading.Current.Humidity = 62
+	reading.Current.Pressure = 1013.2
+	reading.Current.WindSpeed = 12.4
+	reading.Current.WindDirection = 230
+	reading.Current.CloudCover = 40
+	reading.Current.Precipitation = 0
+
+	reading.Hourly.Time = []string{"2026-01-01T00:00", "2026-01-01T01:00"}
+	reading.Hourly.Temperature = []float64{18.5, 18.1}
+	reading.Hourly.FeelsLike = []float64{17.9, 17.5}
+	reading.Hourly.Humidity = []int{62, 64}
+	reading.Hourly.Pressure = []float64{1013.2, 1013.0}
+	reading.Hourly.WindSpeed = []float64{12.4, 11.8}
+	reading.Hourly.WindDirection = []float64{230, 225}
+	reading.Hourly.CloudCover = []int{40, 45}
+	reading.Hourly.Precipitation = []float64{0, 0}
+
+	return &MockProvider{Reading: reading}
+}
+
+func (p *MockProvider) CurrentByLocation(location string, units Units) (*OpenMeteoResponse, error) {
+	return p.Reading, nil
+}
+
+func (p *MockProvider) CurrentByCoordinates(latitude, longitude float64, units Units) (*OpenMeteoResponse, error) {
+	return p.Reading, nil
+}
+
+func (p *MockProvider) ForecastByCoordinates(latitude, longitude float64, hours int, units Units) (*OpenMeteoResponse, error) {
+	return p.Reading, nil
+}
+
+func (p *MockProvider) Name() string {
+	return "mock"
+}
+
+// MockGeocoder is a Geocoder returning canned matches for a small set of
+// known locations, so tests don't depend on a live geocoding API. Unknown
+// queries return no matches, which ResolveLocation turns into a
+// LocationNotFoundError.
+type MockGeocoder struct{}
+
+// NewMockGeocoder returns a Geocoder seeded with a couple of fixture
+// locations.
+func NewMockGeocoder() *MockGeocoder {
+	return &MockGeocoder{}
+}
+
+func (g *MockGeocoder) Resolve(query string) ([]GeocodeMatch, error) {
+	known := map[string]GeoLocation{
+		"london": {Name: "London", Latitude: 51.5074, Longitude: -0.1278, Country: "GB", Timezone: "Europe/London"},
+		"tokyo":  {Name: "Tokyo", Latitude: 35.6762, Longitude: 139.6503, Country: "JP", Timezone: "Asia/Tokyo"},
+	}
+
+	loc, ok := known[strings.ToLower(strings.TrimSpace(query))]
+	if !ok {
+		return nil, nil
+	}
+	return []GeocodeMatch{{Location: loc, Confidence: 1}}, nil
+}
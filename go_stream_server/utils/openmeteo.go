@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+)
+
+const openMeteoCurrentFields = "temperature_2m,apparent_temperature,relative_humidity_2m,surface_pressure,wind_speed_10m,wind_direction_10m,cloud_cover,precipitation"
+const openMeteoHourlyFields = "temperature_2m,apparent_temperature,relative_humidity_2m,surface_pressure,wind_speed_10m,wind_direction_10m,cloud_cover,precipitation"
+
+// OpenMeteoProvider is the default WeatherProvider, backed by the free
+// Open-Meteo forecast API.
+type OpenMeteoProvider struct {
+	geocoder Geocoder
+}
+
+// NewOpenMeteoProvider returns a WeatherProvider backed by Open-Meteo,
+// resolving location names to coordinates via geocoder.
+func NewOpenMeteoProvider(geocoder Geocoder) *OpenMeteoProvider {
+	return &OpenMeteoProvider{geocoder: geocoder}
+}
+
+func (p *OpenMeteoProvider) CurrentByLocation(location string, units Units) (*OpenMeteoResponse, error) {
+	resolved, err := ResolveLocation(p.geocoder, location)
+	if err != nil {
+		return nil, err
+	}
+	return p.CurrentByCoordinates(resolved.Latitude, resolved.Longitude, units)
+}
+
+func (p *OpenMeteoProvider) CurrentByCoordinates(latitude, longitude float64, units Units) (*OpenMeteoResponse, error) {
+	return fetchOpenMeteo(latitude, longitude, units)
+}
+
+func (p *OpenMeteoProvider) ForecastByCoordinates(latitude, longitude float64, hours int, units Units) (*OpenMeteoResponse, error) {
+	// Open-Meteo returns current conditions and the hourly forecast in the
+	// same response, so forecast and current requests share one fetch.
+	return fetchOpenMeteo(latitude, longitude, units)
+}
+
+func (p *OpenMeteoProvider) Name() string {
+	return "openmeteo"
+}
+
+func fetchOpenMeteo(latitude, longitude float64, units Units) (*OpenMeteoResponse, error) {
+	tempUnit := "celsius"
+	windUnit := "kmh"
+	if units == Imperial {
+		tempUnit = "fahrenheit"
+		windUnit = "mph"
+	}
+
+	url := fmt.Sprintf(
+		"https://api.open-meteo.com/v1/forecast?latitude=%.6f&longitude=%.6f&current=%s&hourly=%s&temperature_unit=%s&wind_speed_unit=%s",
+		latitude, longitude, openMeteoCurrentFields, openMeteoHourlyFields, tempUnit, windUnit)
+	log.Printf("Making request to: %s\n", url)
+
+	resp, err := http.Get(url)
+	if err != nil {
+		log.Printf("Error fetching weather data: %v\n", err)
+		return nil, fmt.Errorf("failed to fetch weather data: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.Printf("Error reading response body: %v\n", err)
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+	log.Printf("Raw API response: %s\n", string(body))
+
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("API returned non-200 status code: %d\n", resp.StatusCode)
+		return nil, fmt.Errorf("weather API error: status code %d", resp.StatusCode)
+	}
+
+	var weatherData OpenMeteoResponse
+	if err := json.Unmarshal(body, &weatherData); err != nil {
+		log.Printf("Error decoding weather data: %v\n", err)
+		return nil, fmt.Errorf("failed to decode weather data: %v", err)
+	}
+
+	return &weatherData, nil
+}
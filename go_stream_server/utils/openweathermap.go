@@ -0,0 +1,163 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+)
+
+// owmResponse mirrors the subset of OpenWeatherMap's /data/2.5/weather and
+// /data/2.5/forecast payloads this provider uses.
+type owmResponse struct {
+	Main struct {
+		Temp      float64 `json:"temp"`
+		FeelsLike float64 `json:"feels_like"`
+		Pressure  float64 `json:"pressure"`
+		Humidity  int     `json:"humidity"`
+	} `json:"main"`
+	Wind struct {
+		Speed float64 `json:"speed"`
+		Deg   float64 `json:"deg"`
+	} `json:"wind"`
+	Clouds struct {
+		All int `json:"all"`
+	} `json:"clouds"`
+	Rain struct {
+		OneHour float64 `json:"1h"`
+	} `json:"rain"`
+	Cod     int    `json:"cod"`
+	Message string `json:"message"`
+	List    []struct {
+		Dt   int64 `json:"dt"`
+		Main struct {
+			Temp      float64 `json:"temp"`
+			FeelsLike float64 `json:"feels_like"`
+			Pressure  float64 `json:"pressure"`
+			Humidity  int     `json:"humidity"`
+		} `json:"main"`
+		Wind struct {
+			Speed float64 `json:"speed"`
+			Deg   float64 `json:"deg"`
+		} `json:"wind"`
+		Clouds struct {
+			All int `json:"all"`
+		} `json:"clouds"`
+		Rain struct {
+			ThreeHour float64 `json:"3h"`
+		} `json:"rain"`
+	} `json:"list"`
+}
+
+// OpenWeatherMapProvider is a WeatherProvider backed by the OpenWeatherMap
+// current conditions and 5-day/3-hour forecast APIs.
+type OpenWeatherMapProvider struct {
+	apiKey   string
+	geocoder Geocoder
+}
+
+// NewOpenWeatherMapProvider returns a WeatherProvider backed by
+// OpenWeatherMap, authenticated with apiKey and resolving location names
+// to coordinates via geocoder.
+func NewOpenWeatherMapProvider(apiKey string, geocoder Geocoder) *OpenWeatherMapProvider {
+	return &OpenWeatherMapProvider{apiKey: apiKey, geocoder: geocoder}
+}
+
+func (p *OpenWeatherMapProvider) CurrentByLocation(location string, units Units) (*OpenMeteoResponse, error) {
+	resolved, err := ResolveLocation(p.geocoder, location)
+	if err != nil {
+		return nil, err
+	}
+	return p.CurrentByCoordinates(resolved.Latitude, resolved.Longitude, units)
+}
+
+func (p *OpenWeatherMapProvider) CurrentByCoordinates(latitude, longitude float64, units Units) (*OpenMeteoResponse, error) {
+	url := fmt.Sprintf(
+		"https://api.openweathermap.org/data/2.5/weather?lat=%.6f&lon=%.6f&units=%s&appid=%s",
+		latitude, longitude, owmUnits(units), p.apiKey)
+
+	var raw owmResponse
+	if err := owmGet(url, &raw); err != nil {
+		return nil, err
+	}
+
+	data := &OpenMeteoResponse{}
+	data.Current.Temperature = raw.Main.Temp
+	data.Current.FeelsLike = raw.Main.FeelsLike
+	data.Current.Humidity = raw.Main.Humidity
+	data.Current.Pressure = raw.Main.Pressure
+	data.Current.WindSpeed = raw.Wind.Speed
+	data.Current.WindDirection = raw.Wind.Deg
+	data.Current.CloudCover = raw.Clouds.All
+	data.Current.Precipitation = raw.Rain.OneHour
+
+	return data, nil
+}
+
+func (p *OpenWeatherMapProvider) ForecastByCoordinates(latitude, longitude float64, hours int, units Units) (*OpenMeteoResponse, error) {
+	url := fmt.Sprintf(
+		"https://api.openweathermap.org/data/2.5/forecast?lat=%.6f&lon=%.6f&units=%s&appid=%s",
+		latitude, longitude, owmUnits(units), p.apiKey)
+
+	var raw owmResponse
+	if err := owmGet(url, &raw); err != nil {
+		return nil, err
+	}
+
+	data := &OpenMeteoResponse{}
+	entries := raw.List
+	if hours > 0 && hours < len(entries)*3 {
+		entries = entries[:(hours+2)/3]
+	}
+
+	for _, entry := range entries {
+		data.Hourly.Time = append(data.Hourly.Time, fmt.Sprintf("%d", entry.Dt))
+		data.Hourly.Temperature = append(data.Hourly.Temperature, entry.Main.Temp)
+		data.Hourly.FeelsLike = append(data.Hourly.FeelsLike, entry.Main.FeelsLike)
+		data.Hourly.Humidity = append(data.Hourly.Humidity, entry.Main.Humidity)
+		data.Hourly.Pressure = append(data.Hourly.Pressure, entry.Main.Pressure)
+		data.Hourly.WindSpeed = append(data.Hourly.WindSpeed, entry.Wind.Speed)
+		data.Hourly.WindDirection = append(data.Hourly.WindDirection, entry.Wind.Deg)
+		data.Hourly.CloudCover = append(data.Hourly.CloudCover, entry.Clouds.All)
+		data.Hourly.Precipitation = append(data.Hourly.Precipitation, entry.Rain.ThreeHour)
+	}
+
+	return data, nil
+}
+
+func (p *OpenWeatherMapProvider) Name() string {
+	return "openweathermap"
+}
+
+func owmUnits(units Units) string {
+	if units == Imperial {
+		return "imperial"
+	}
+	return "metric"
+}
+
+func owmGet(url string, out *owmResponse) error {
+	log.Printf("Making request to: %s\n", redactAPIKeyFromURL(url))
+
+	resp, err := http.Get(url)
+	if err != nil {
+		return fmt.Errorf("failed to fetch weather data: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("weather API error: status code %d", resp.StatusCode)
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to decode weather data: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrMissingTokens is returned by NewProviderFromEnv when the provider
+// selected via WEATHER_PROVIDER requires an API key that isn't set.
+var ErrMissingTokens = errors.New("missing required environment tokens for weather provider")
+
+// WeatherProvider abstracts over upstream weather APIs so handlers don't
+// depend on any one vendor's request shape or JSON schema. Every
+// implementation normalizes its response into OpenMeteoResponse.
+type WeatherProvider interface {
+	CurrentByLocation(location string, units Units) (*OpenMeteoResponse, error)
+	CurrentByCoordinates(latitude, longitude float64, units Units) (*OpenMeteoResponse, error)
+	ForecastByCoordinates(latitude, longitude float64, hours int, units Units) (*OpenMeteoResponse, error)
+
+	// Name identifies the provider (e.g. "openmeteo", "openweathermap"), so
+	// callers like Cache can namespace persisted state per provider.
+	Name() string
+}
+
+// NewProviderFromEnv selects a WeatherProvider based on the WEATHER_PROVIDER
+// environment variable ("openmeteo", "openweathermap", "meteologix", or
+// "mock"; defaults to "openmeteo"), returning ErrMissingTokens if the
+// selected provider needs an API key that isn't set. geocoder resolves
+// location names to coordinates for providers that need it.
+func NewProviderFromEnv(geocoder Geocoder) (WeatherProvider, error) {
+	switch strings.ToLower(os.Getenv("WEATHER_PROVIDER")) {
+	case "", "openmeteo":
+		return NewOpenMeteoProvider(geocoder), nil
+
+	case "openweathermap":
+		apiKey := os.Getenv("OPENWEATHERMAP_API_KEY")
+		if apiKey == "" {
+			return nil, ErrMissingTokens
+		}
+		return NewOpenWeatherMapProvider(apiKey, geocoder), nil
+
+	case "meteologix":
+		apiKey := os.Getenv("METEOLOGIX_API_KEY")
+		if apiKey == "" {
+			return nil, ErrMissingTokens
+		}
+		return NewMeteologixProvider(apiKey, geocoder), nil
+
+	case "mock":
+		return NewMockProvider(), nil
+
+	default:
+		return nil, fmt.Errorf("unknown weather provider: %s", os.Getenv("WEATHER_PROVIDER"))
+	}
+}
@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewProviderFromEnv(t *testing.T) {
+	geocoder := NewMockGeocoder()
+
+	testCases := []struct {
+		name           string
+		provider       string
+		owmKey         string
+		meteologixKey  string
+		wantProvider   string
+		wantErr        error
+		wantGenericErr bool
+	}{
+		{
+			name:         "unset defaults to openmeteo",
+			provider:     "",
+			wantProvider: "openmeteo",
+		},
+		{
+			name:         "openmeteo explicit",
+			provider:     "openmeteo",
+			wantProvider: "openmeteo",
+		},
+		{
+			name:         "mock",
+			provider:     "mock",
+			wantProvider: "mock",
+		},
+		{
+			name:         "case insensitive",
+			provider:     "OpenWeatherMap",
+			owmKey:       "test-key",
+			wantProvider: "openweathermap",
+		},
+		{
+			name:     "openweathermap missing key",
+			provider: "openweathermap",
+			wantErr:  ErrMissingTokens,
+		},
+		{
+			name:          "meteologix missing key",
+			provider:      "meteologix",
+			meteologixKey: "",
+			wantErr:       ErrMissingTokens,
+		},
+		{
+			name:          "meteologix with key",
+			provider:      "meteologix",
+			meteologixKey: "test-key",
+			wantProvider:  "meteologix",
+		},
+		{
+			name:           "unknown provider",
+			provider:       "wunderground",
+			wantGenericErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("WEATHER_PROVIDER", tc.provider)
+			t.Setenv("OPENWEATHERMAP_API_KEY", tc.owmKey)
+			t.Setenv("METEOLOGIX_API_KEY", tc.meteologixKey)
+
+			provider, err := NewProviderFromEnv(geocoder)
+
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+				}
+				return
+			}
+
+			if tc.wantGenericErr {
+				if err == nil {
+					t.Fatal("expected an error for an unknown provider, got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if provider.Name() != tc.wantProvider {
+				t.Errorf("expected provider %q, got %q", tc.wantProvider, provider.Name())
+			}
+		})
+	}
+}
@@ -1,68 +1,47 @@
 package utils
 
-import (
-	"encoding/json"
-	"fmt"
-	"io"
-	"log"
-	"net/http"
-)
-
-type OpenMeteoResponse struct {
-	Current struct {
-		Temperature float64 `json:"temperature_2m"`
-	} `json:"current"`
-	Error   bool   `json:"error"`
-	Reason  string `json:"reason"`
-}
-
-func GetWeatherData(latitude, longitude float64) (*OpenMeteoResponse, error) {
-	url := fmt.Sprintf("https://api.open-meteo.com/v1/forecast?latitude=%.6f&longitude=%.6f&current=temperature_2m", 
-		latitude, longitude)
-	log.Printf("Making request to: %s\n", url)
+import "regexp"
 
-	resp, err := http.Get(url)
-	if err != nil {
-		log.Printf("Error fetching weather data: %v\n", err)
-		return nil, fmt.Errorf("failed to fetch weather data: %v", err)
-	}
-	defer resp.Body.Close()
+var apiKeyParamPattern = regexp.MustCompile(`(?i)([?&](?:appid|apikey)=)[^&]+`)
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		log.Printf("Error reading response body: %v\n", err)
-		return nil, fmt.Errorf("failed to read response: %v", err)
-	}
-	log.Printf("Raw API response: %s\n", string(body))
+// redactAPIKeyFromURL returns url with any appid/apiKey query parameter
+// value replaced by "REDACTED", safe to pass to a logger.
+func redactAPIKeyFromURL(url string) string {
+	return apiKeyParamPattern.ReplaceAllString(url, "${1}REDACTED")
+}
 
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("API returned non-200 status code: %d\n", resp.StatusCode)
-		return nil, fmt.Errorf("weather API error: status code %d", resp.StatusCode)
-	}
+// Units selects which unit system a WeatherProvider should respond in.
+type Units string
 
-	var weatherData OpenMeteoResponse
-	if err := json.Unmarshal(body, &weatherData); err != nil {
-		log.Printf("Error decoding weather data: %v\n", err)
-		return nil, fmt.Errorf("failed to decode weather data: %v", err)
-	}
+const (
+	Metric   Units = "metric"
+	Imperial Units = "imperial"
+)
 
-	return &weatherData, nil
+// OpenMeteoResponse is the canonical weather payload every WeatherProvider
+// normalizes its response into, named for the provider it was modeled on.
+type OpenMeteoResponse struct {
+	Current struct {
+		Temperature   float64 `json:"temperature_2m"`
+		FeelsLike     float64 `json:"apparent_temperature"`
+		Humidity      int     `json:"relative_humidity_2m"`
+		Pressure      float64 `json:"surface_pressure"`
+		WindSpeed     float64 `json:"wind_speed_10m"`
+		WindDirection float64 `json:"wind_direction_10m"`
+		CloudCover    int     `json:"cloud_cover"`
+		Precipitation float64 `json:"precipitation"`
+	} `json:"current"`
+	Hourly struct {
+		Time          []string  `json:"time"`
+		Temperature   []float64 `json:"temperature_2m"`
+		FeelsLike     []float64 `json:"apparent_temperature"`
+		Humidity      []int     `json:"relative_humidity_2m"`
+		Pressure      []float64 `json:"surface_pressure"`
+		WindSpeed     []float64 `json:"wind_speed_10m"`
+		WindDirection []float64 `json:"wind_direction_10m"`
+		CloudCover    []int     `json:"cloud_cover"`
+		Precipitation []float64 `json:"precipitation"`
+	} `json:"hourly"`
+	Error  bool   `json:"error"`
+	Reason string `json:"reason"`
 }
-
-// GetCoordinatesForLocation returns latitude and longitude for a given location
-func GetCoordinatesForLocation(location string) (float64, float64) {
-	coordinates := map[string]struct{ lat, lon float64 }{
-		"new york":      {40.7128, -74.0060},
-		"london":        {51.5074, -0.1278},
-		"tokyo":         {35.6762, 139.6503},
-		"paris":         {48.8566, 2.3522},
-		"sydney":        {-33.8688, 151.2093},
-		"san francisco": {37.7749, -122.4194},
-	}
-
-	if coord, ok := coordinates[location]; ok {
-		return coord.lat, coord.lon
-	}
-	// Return default coordinates (New York) if location not found
-	return 40.7128, -74.0060
-} 
\ No newline at end of file
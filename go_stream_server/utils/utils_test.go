@@ -0,0 +1,41 @@
+package utils
+
+import "testing"
+
+func TestRedactAPIKeyFromURL(t *testing.T) {
+	testCases := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{
+			name: "openweathermap appid",
+			url:  "https://api.openweathermap.org/data/2.5/weather?lat=51.500000&lon=-0.130000&units=metric&appid=supersecretkey",
+			want: "https://api.openweathermap.org/data/2.5/weather?lat=51.500000&lon=-0.130000&units=metric&appid=REDACTED",
+		},
+		{
+			name: "meteologix apiKey",
+			url:  "https://api.meteologix.com/v1/observations?lat=51.5&lon=-0.13&units=metric&apiKey=supersecretkey",
+			want: "https://api.meteologix.com/v1/observations?lat=51.5&lon=-0.13&units=metric&apiKey=REDACTED",
+		},
+		{
+			name: "key is case-insensitively matched but value always redacted",
+			url:  "https://example.com/weather?APIKEY=abc123&units=metric",
+			want: "https://example.com/weather?APIKEY=REDACTED&units=metric",
+		},
+		{
+			name: "no key param is left untouched",
+			url:  "https://api.open-meteo.com/v1/forecast?latitude=51.5&longitude=-0.13",
+			want: "https://api.open-meteo.com/v1/forecast?latitude=51.5&longitude=-0.13",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := redactAPIKeyFromURL(tc.url)
+			if got != tc.want {
+				t.Errorf("redactAPIKeyFromURL(%q) = %q, want %q", tc.url, got, tc.want)
+			}
+		})
+	}
+}